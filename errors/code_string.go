@@ -0,0 +1,78 @@
+// Copyright 2017 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package errors
+
+import "strconv"
+
+// codeNames holds the canonical name of each Code, indexed by its numeric
+// value. It mirrors grpc-go's generated codes/code_string.go, since Code is
+// expected to map 1:1 to codes.Code.
+var codeNames = [...]string{
+	OK:                 "OK",
+	Canceled:           "Canceled",
+	Unknown:            "Unknown",
+	InvalidArgument:    "InvalidArgument",
+	DeadlineExceeded:   "DeadlineExceeded",
+	NotFound:           "NotFound",
+	AlreadyExists:      "AlreadyExists",
+	PermissionDenied:   "PermissionDenied",
+	ResourceExhausted:  "ResourceExhausted",
+	FailedPrecondition: "FailedPrecondition",
+	Aborted:            "Aborted",
+	OutOfRange:         "OutOfRange",
+	Unimplemented:      "Unimplemented",
+	Internal:           "Internal",
+	Unavailable:        "Unavailable",
+	DataLoss:           "DataLoss",
+	Unauthenticated:    "Unauthenticated",
+}
+
+// String returns the canonical name of c, e.g. "NotFound", or
+// "Code(<n>)" if c is not one of the constants defined in this package.
+func (c Code) String() string {
+	if int(c) < len(codeNames) {
+		return codeNames[c]
+	}
+	return "Code(" + strconv.FormatUint(uint64(c), 10) + ")"
+}
+
+// ParseCode returns the Code whose String() equals name. It accepts the
+// numeric "Code(<n>)" form produced by String() for unknown codes, in
+// addition to the canonical names ("OK", "NotFound", etc).
+func ParseCode(name string) (Code, error) {
+	for i, n := range codeNames {
+		if n == name {
+			return Code(i), nil
+		}
+	}
+	if n, ok := parseUnknownCode(name); ok {
+		return n, nil
+	}
+	return 0, Errorf(InvalidArgument, "unrecognized code name: %q", name)
+}
+
+// parseUnknownCode recognizes the "Code(<n>)" form produced by String() for
+// codes with no canonical name.
+func parseUnknownCode(name string) (Code, bool) {
+	const prefix, suffix = "Code(", ")"
+	if len(name) <= len(prefix)+len(suffix) || name[:len(prefix)] != prefix || name[len(name)-len(suffix):] != suffix {
+		return 0, false
+	}
+	n, err := strconv.ParseUint(name[len(prefix):len(name)-len(suffix)], 10, 32)
+	if err != nil {
+		return 0, false
+	}
+	return Code(n), true
+}
@@ -0,0 +1,84 @@
+// Copyright 2017 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package errors
+
+import "testing"
+
+func TestCodeStringParseRoundTrip(t *testing.T) {
+	for c := OK; c <= Unauthenticated; c++ {
+		name := c.String()
+		got, err := ParseCode(name)
+		if err != nil {
+			t.Errorf("ParseCode(%q) failed: %v", name, err)
+			continue
+		}
+		if got != c {
+			t.Errorf("ParseCode(%q) = %v, want %v", name, got, c)
+		}
+	}
+}
+
+func TestCodeStringUnknown(t *testing.T) {
+	c := Code(999)
+	if got, want := c.String(), "Code(999)"; got != want {
+		t.Errorf("Code(999).String() = %q, want %q", got, want)
+	}
+
+	got, err := ParseCode("Code(999)")
+	if err != nil {
+		t.Fatalf("ParseCode(%q) failed: %v", "Code(999)", err)
+	}
+	if got != c {
+		t.Errorf("ParseCode(%q) = %v, want %v", "Code(999)", got, c)
+	}
+}
+
+func TestParseCodeInvalid(t *testing.T) {
+	if _, err := ParseCode("NotACode"); err == nil {
+		t.Error("ParseCode(\"NotACode\") succeeded, want error")
+	}
+}
+
+func TestCodeJSONRoundTrip(t *testing.T) {
+	for _, c := range []Code{OK, NotFound, FailedPrecondition, Code(999)} {
+		b, err := c.MarshalJSON()
+		if err != nil {
+			t.Fatalf("MarshalJSON() failed: %v", err)
+		}
+		var got Code
+		if err := got.UnmarshalJSON(b); err != nil {
+			t.Fatalf("UnmarshalJSON(%s) failed: %v", b, err)
+		}
+		if got != c {
+			t.Errorf("JSON round-trip of %v = %v", c, got)
+		}
+	}
+}
+
+func TestCodeTextRoundTrip(t *testing.T) {
+	for _, c := range []Code{OK, NotFound, FailedPrecondition, Code(999)} {
+		b, err := c.MarshalText()
+		if err != nil {
+			t.Fatalf("MarshalText() failed: %v", err)
+		}
+		var got Code
+		if err := got.UnmarshalText(b); err != nil {
+			t.Fatalf("UnmarshalText(%s) failed: %v", b, err)
+		}
+		if got != c {
+			t.Errorf("text round-trip of %v = %v", c, got)
+		}
+	}
+}
@@ -14,7 +14,12 @@
 
 package errors
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+)
 
 // Code mirrors gRPC's codes.Code.
 type Code uint32
@@ -144,6 +149,45 @@ const (
 	// you do so.
 )
 
+// MarshalJSON implements json.Marshaler, encoding c as its canonical name
+// (e.g. "NotFound") rather than its numeric value, so config files and API
+// responses stay human-readable.
+func (c Code) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + c.String() + `"`), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler, accepting the canonical name
+// produced by MarshalJSON.
+func (c *Code) UnmarshalJSON(b []byte) error {
+	s := string(b)
+	if len(s) < 2 || s[0] != '"' || s[len(s)-1] != '"' {
+		return Errorf(InvalidArgument, "invalid Code JSON %q: not a string", s)
+	}
+	parsed, err := ParseCode(s[1 : len(s)-1])
+	if err != nil {
+		return err
+	}
+	*c = parsed
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler, encoding c as its canonical
+// name (e.g. "NotFound").
+func (c Code) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, accepting the canonical
+// name produced by MarshalText.
+func (c *Code) UnmarshalText(text []byte) error {
+	parsed, err := ParseCode(string(text))
+	if err != nil {
+		return err
+	}
+	*c = parsed
+	return nil
+}
+
 // TrillianError associates an error message with a failure code in order to
 // make error translation possible by other layers (e.g., TrillianError to
 // gRPC).
@@ -158,8 +202,12 @@ type TrillianError interface {
 }
 
 type trillianError struct {
-	code    Code
-	message string
+	code          Code
+	message       string
+	cause         error
+	details       []proto.Message
+	retryAfter    time.Duration
+	hasRetryAfter bool
 }
 
 func (e *trillianError) Error() string {
@@ -170,6 +218,12 @@ func (e *trillianError) Code() Code {
 	return e.code
 }
 
+// Unwrap returns the cause of e, if any, allowing errors.Is and errors.As to
+// see through a TrillianError to whatever error it wraps.
+func (e *trillianError) Unwrap() error {
+	return e.cause
+}
+
 // ErrorCode returns the assigned Code if err is a TrillianError.
 // If err is nil, OK is returned.
 // If err is not a TrillianError, Unknown is returned.
@@ -190,7 +244,7 @@ func ErrorCode(err error) Code {
 // therefore both code and message should be chosen from the perspective of the
 // RPC caller.
 func Errorf(code Code, format string, a ...interface{}) error {
-	return &trillianError{code, fmt.Sprintf(format, a...)}
+	return &trillianError{code: code, message: fmt.Sprintf(format, a...)}
 }
 
 // New creates a TrillianError from the specified code and message.
@@ -199,5 +253,51 @@ func Errorf(code Code, format string, a ...interface{}) error {
 // therefore both code and message should be chosen from the perspective of the
 // RPC caller.
 func New(code Code, msg string) error {
-	return &trillianError{code, msg}
+	return &trillianError{code: code, message: msg}
+}
+
+// Wrap creates a TrillianError from the specified code and message, recording
+// cause as its underlying error. The cause is reachable via Unwrap, so
+// errors.Is and errors.As can match against it even though the message
+// presented to the RPC caller is the one passed here.
+func Wrap(code Code, cause error, format string, a ...interface{}) error {
+	return &trillianError{code: code, message: fmt.Sprintf(format, a...), cause: cause}
+}
+
+// WithDetails returns a copy of err carrying the given detail protos in
+// addition to any it already has. details mirrors the role of
+// google.rpc.Status.details in gRPC: typed, structured payloads (e.g.
+// errdetails.RetryInfo, errdetails.QuotaFailure) that a caller can extract
+// with Details instead of parsing the error message.
+//
+// If err is nil, nil is returned. If err is not a TrillianError, it is
+// wrapped in one that preserves its code (per ErrorCode) and message, with
+// err set as the cause so Unwrap still reaches it.
+func WithDetails(err error, details ...proto.Message) error {
+	if err == nil {
+		return nil
+	}
+	if te, ok := err.(*trillianError); ok {
+		cp := *te
+		cp.details = append(append([]proto.Message{}, te.details...), details...)
+		return &cp
+	}
+	return &trillianError{code: ErrorCode(err), message: err.Error(), cause: err, details: details}
+}
+
+// Details returns the detail protos attached to err via WithDetails, walking
+// the Unwrap chain if err itself carries none. It returns nil if no detail
+// protos are found anywhere in the chain.
+func Details(err error) []proto.Message {
+	for err != nil {
+		if te, ok := err.(*trillianError); ok && len(te.details) > 0 {
+			return te.details
+		}
+		unwrapper, ok := err.(interface{ Unwrap() error })
+		if !ok {
+			break
+		}
+		err = unwrapper.Unwrap()
+	}
+	return nil
 }
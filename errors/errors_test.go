@@ -0,0 +1,96 @@
+// Copyright 2017 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package errors
+
+import (
+	stderrors "errors"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func TestWrapUnwrap(t *testing.T) {
+	cause := stderrors.New("underlying failure")
+	err := Wrap(Internal, cause, "wrapped: %v", cause)
+
+	if got := ErrorCode(err); got != Internal {
+		t.Errorf("ErrorCode() = %v, want %v", got, Internal)
+	}
+	if !stderrors.Is(err, cause) {
+		t.Errorf("errors.Is(err, cause) = false, want true")
+	}
+	var got *trillianError
+	if !stderrors.As(err, &got) {
+		t.Errorf("errors.As(err, &trillianError) = false, want true")
+	}
+}
+
+func TestWithDetailsAndDetails(t *testing.T) {
+	d1 := durationpb.New(0)
+	d2 := durationpb.New(0)
+
+	t.Run("nil error", func(t *testing.T) {
+		if got := WithDetails(nil, d1); got != nil {
+			t.Errorf("WithDetails(nil, ...) = %v, want nil", got)
+		}
+	})
+
+	t.Run("trillian error accumulates details", func(t *testing.T) {
+		err := New(NotFound, "missing")
+		err = WithDetails(err, d1)
+		err = WithDetails(err, d2)
+
+		got := Details(err)
+		if len(got) != 2 {
+			t.Fatalf("Details() = %d protos, want 2", len(got))
+		}
+		if got[0] != proto.Message(d1) || got[1] != proto.Message(d2) {
+			t.Errorf("Details() = %v, want [%v, %v]", got, d1, d2)
+		}
+	})
+
+	t.Run("non-trillian error is wrapped preserving code and cause", func(t *testing.T) {
+		base := stderrors.New("boom")
+		err := WithDetails(base, d1)
+
+		if got := ErrorCode(err); got != Unknown {
+			t.Errorf("ErrorCode() = %v, want %v", got, Unknown)
+		}
+		if !stderrors.Is(err, base) {
+			t.Errorf("errors.Is(err, base) = false, want true")
+		}
+		if got := Details(err); len(got) != 1 || got[0] != proto.Message(d1) {
+			t.Errorf("Details() = %v, want [%v]", got, d1)
+		}
+	})
+
+	t.Run("details found through Unwrap chain", func(t *testing.T) {
+		withDetails := WithDetails(New(NotFound, "missing"), d1)
+		outer := Wrap(NotFound, withDetails, "outer")
+
+		got := Details(outer)
+		if len(got) != 1 || got[0] != proto.Message(d1) {
+			t.Errorf("Details(outer) = %v, want [%v]", got, d1)
+		}
+	})
+
+	t.Run("no details anywhere in chain", func(t *testing.T) {
+		outer := Wrap(Internal, stderrors.New("plain"), "outer")
+		if got := Details(outer); got != nil {
+			t.Errorf("Details() = %v, want nil", got)
+		}
+	})
+}
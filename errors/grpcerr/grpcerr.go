@@ -0,0 +1,116 @@
+// Copyright 2017 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package grpcerr bridges the errors package and gRPC's status package, so
+// that Trillian's RPC servers and clients can speak TrillianErrors
+// internally while still exchanging the codes.Code/status.Status values that
+// actually go over the wire.
+package grpcerr
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/protoadapt"
+
+	terrors "github.com/google/trillian/errors"
+)
+
+// ToStatus converts err into a gRPC status, preserving its code, message, and
+// any detail protos attached via terrors.WithDetails.
+//
+// If err is nil, a status representing codes.OK is returned. If err is not a
+// terrors.TrillianError, its code is derived via terrors.ErrorCode (so a
+// plain error becomes codes.Unknown, matching ErrorCode's own behavior).
+func ToStatus(err error) *status.Status {
+	if err == nil {
+		return status.New(codes.OK, "")
+	}
+	s := status.New(codes.Code(terrors.ErrorCode(err)), err.Error())
+	details := terrors.Details(err)
+	if len(details) == 0 {
+		return s
+	}
+	v1details := make([]protoadapt.MessageV1, len(details))
+	for i, d := range details {
+		v1details[i] = protoadapt.MessageV1Of(d)
+	}
+	sd, detailErr := s.WithDetails(v1details...)
+	if detailErr != nil {
+		// A detail proto couldn't be packed into an Any. Prefer returning the
+		// status without details over losing the underlying error entirely.
+		return s
+	}
+	return sd
+}
+
+// FromStatus converts a gRPC status back into a TrillianError, the inverse of
+// ToStatus. The returned error's Code and Error() match s, and any detail
+// protos carried by s are reattached so terrors.Details can retrieve them.
+//
+// FromStatus returns nil for a nil status or one reporting codes.OK.
+func FromStatus(s *status.Status) error {
+	if s == nil || s.Code() == codes.OK {
+		return nil
+	}
+	err := terrors.New(terrors.Code(s.Code()), s.Message())
+
+	raw := s.Details()
+	if len(raw) == 0 {
+		return err
+	}
+	details := make([]proto.Message, 0, len(raw))
+	for _, d := range raw {
+		// status.Details unpacks each Any into the legacy v1 proto.Message
+		// interface; adapt it to v2 (what terrors.Details/WithDetails deal
+		// in) before handing it back.
+		if v1, ok := d.(protoadapt.MessageV1); ok {
+			details = append(details, protoadapt.MessageV2Of(v1))
+			continue
+		}
+		if v2, ok := d.(proto.Message); ok {
+			details = append(details, v2)
+		}
+	}
+	if len(details) == 0 {
+		return err
+	}
+	return terrors.WithDetails(err, details...)
+}
+
+// UnaryServerInterceptor converts any error returned by a unary RPC handler
+// into a gRPC status via ToStatus, so server code can return TrillianErrors
+// (or plain errors) directly instead of calling status.Errorf itself.
+func UnaryServerInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	resp, err := handler(ctx, req)
+	if err != nil {
+		return resp, ToStatus(err).Err()
+	}
+	return resp, nil
+}
+
+// UnaryClientInterceptor converts the gRPC status returned by an RPC back
+// into a TrillianError via FromStatus, so client code can use terrors
+// helpers (ErrorCode, errors.Is, errors.As, Details) instead of
+// status.FromError.
+func UnaryClientInterceptor(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	err := invoker(ctx, method, req, reply, cc, opts...)
+	if err == nil {
+		return nil
+	}
+	return FromStatus(status.Convert(err))
+}
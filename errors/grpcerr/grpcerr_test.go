@@ -0,0 +1,70 @@
+// Copyright 2017 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpcerr
+
+import (
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	terrors "github.com/google/trillian/errors"
+)
+
+func TestToStatusFromStatus(t *testing.T) {
+	err := terrors.New(terrors.NotFound, "no such tree")
+
+	s := ToStatus(err)
+	if got, want := s.Code(), codes.NotFound; got != want {
+		t.Errorf("ToStatus(err).Code() = %v, want %v", got, want)
+	}
+	if got, want := s.Message(), "no such tree"; got != want {
+		t.Errorf("ToStatus(err).Message() = %q, want %q", got, want)
+	}
+
+	got := FromStatus(s)
+	if terrors.ErrorCode(got) != terrors.NotFound {
+		t.Errorf("FromStatus(s) code = %v, want %v", terrors.ErrorCode(got), terrors.NotFound)
+	}
+	if got.Error() != "no such tree" {
+		t.Errorf("FromStatus(s).Error() = %q, want %q", got.Error(), "no such tree")
+	}
+}
+
+func TestToStatusFromStatusOK(t *testing.T) {
+	if got := ToStatus(nil).Code(); got != codes.OK {
+		t.Errorf("ToStatus(nil).Code() = %v, want OK", got)
+	}
+	if got := FromStatus(nil); got != nil {
+		t.Errorf("FromStatus(nil) = %v, want nil", got)
+	}
+}
+
+func TestToStatusFromStatusDetailsRoundTrip(t *testing.T) {
+	detail := durationpb.New(0)
+	err := terrors.WithDetails(terrors.New(terrors.ResourceExhausted, "quota"), detail)
+
+	s := ToStatus(err)
+	got := FromStatus(s)
+
+	details := terrors.Details(got)
+	if len(details) != 1 {
+		t.Fatalf("Details(FromStatus(ToStatus(err))) = %d protos, want 1", len(details))
+	}
+	if !proto.Equal(details[0], detail) {
+		t.Errorf("round-tripped detail = %v, want %v", details[0], detail)
+	}
+}
@@ -0,0 +1,135 @@
+// Copyright 2017 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package errors
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// httpStatusClientClosedRequest is the unofficial "Client Closed Request"
+// status used by nginx and grpc-gateway for Canceled; net/http has no
+// constant for it.
+const httpStatusClientClosedRequest = 499
+
+// HTTPStatus maps c to an HTTP status code, following the standard
+// gRPC-to-HTTP mapping used by grpc-gateway style REST/JSON facades.
+func HTTPStatus(c Code) int {
+	switch c {
+	case OK:
+		return http.StatusOK
+	case Canceled:
+		return httpStatusClientClosedRequest
+	case InvalidArgument, FailedPrecondition, OutOfRange:
+		return http.StatusBadRequest
+	case DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case NotFound:
+		return http.StatusNotFound
+	case AlreadyExists, Aborted:
+		return http.StatusConflict
+	case PermissionDenied:
+		return http.StatusForbidden
+	case Unauthenticated:
+		return http.StatusUnauthorized
+	case ResourceExhausted:
+		return http.StatusTooManyRequests
+	case Unimplemented:
+		return http.StatusNotImplemented
+	case Unavailable:
+		return http.StatusServiceUnavailable
+	case Unknown, Internal, DataLoss:
+		return http.StatusInternalServerError
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// FromHTTPStatus maps an HTTP status code back to a Code, the inverse of
+// HTTPStatus. Several Codes share an HTTP status (e.g. FailedPrecondition
+// and InvalidArgument both map to 400); FromHTTPStatus returns the Code
+// listed first for that status in HTTPStatus.
+func FromHTTPStatus(status int) Code {
+	switch status {
+	case http.StatusOK:
+		return OK
+	case httpStatusClientClosedRequest:
+		return Canceled
+	case http.StatusBadRequest:
+		return InvalidArgument
+	case http.StatusGatewayTimeout:
+		return DeadlineExceeded
+	case http.StatusNotFound:
+		return NotFound
+	case http.StatusConflict:
+		return AlreadyExists
+	case http.StatusForbidden:
+		return PermissionDenied
+	case http.StatusUnauthorized:
+		return Unauthenticated
+	case http.StatusTooManyRequests:
+		return ResourceExhausted
+	case http.StatusNotImplemented:
+		return Unimplemented
+	case http.StatusServiceUnavailable:
+		return Unavailable
+	case http.StatusInternalServerError:
+		return Unknown
+	default:
+		return Unknown
+	}
+}
+
+// httpErrorBody is the wire shape WriteError emits.
+type httpErrorBody struct {
+	Code    string            `json:"code"`
+	Message string            `json:"message"`
+	Details []json.RawMessage `json:"details,omitempty"`
+}
+
+// WriteError writes err to w as a JSON body of the form
+// {"code":"NotFound","message":"...","details":[...]}, using HTTPStatus(err's
+// code) as the status line. Any detail protos attached via WithDetails are
+// included, each marshaled with protojson.
+//
+// WriteError lets grpc-gateway-style REST facades in front of the log/map
+// RPC servers return a consistent error shape without a hand-rolled switch
+// statement in every handler.
+//
+// WriteError is a no-op if err is nil; callers should only invoke it once
+// they know a request failed.
+func WriteError(w http.ResponseWriter, err error) {
+	if err == nil {
+		return
+	}
+	code := ErrorCode(err)
+	body := httpErrorBody{
+		Code:    code.String(),
+		Message: err.Error(),
+	}
+	for _, d := range Details(err) {
+		b, mErr := protojson.Marshal(d)
+		if mErr != nil {
+			continue
+		}
+		body.Details = append(body.Details, json.RawMessage(b))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(HTTPStatus(code))
+	_ = json.NewEncoder(w).Encode(body)
+}
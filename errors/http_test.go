@@ -0,0 +1,94 @@
+// Copyright 2017 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package errors
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHTTPStatusFromHTTPStatusRoundTrip(t *testing.T) {
+	// Only one Code maps to each HTTP status per the documented tie-break
+	// (the first Code listed in HTTPStatus for that status), so round-tripping
+	// through both directions should return the original Code for these.
+	for _, c := range []Code{
+		OK, Canceled, InvalidArgument, DeadlineExceeded, NotFound, AlreadyExists,
+		PermissionDenied, Unauthenticated, ResourceExhausted, Unimplemented,
+		Unavailable, Unknown,
+	} {
+		status := HTTPStatus(c)
+		if got := FromHTTPStatus(status); got != c {
+			t.Errorf("FromHTTPStatus(HTTPStatus(%v)=%d) = %v, want %v", c, status, got, c)
+		}
+	}
+}
+
+func TestHTTPStatusSharedCodes(t *testing.T) {
+	// FailedPrecondition and OutOfRange share InvalidArgument's 400; Aborted
+	// shares AlreadyExists' 409; Internal and DataLoss share Unknown's 500.
+	for _, tc := range []struct {
+		code Code
+		want int
+	}{
+		{FailedPrecondition, http.StatusBadRequest},
+		{OutOfRange, http.StatusBadRequest},
+		{Aborted, http.StatusConflict},
+		{Internal, http.StatusInternalServerError},
+		{DataLoss, http.StatusInternalServerError},
+	} {
+		if got := HTTPStatus(tc.code); got != tc.want {
+			t.Errorf("HTTPStatus(%v) = %d, want %d", tc.code, got, tc.want)
+		}
+	}
+}
+
+func TestFromHTTPStatusUnknown(t *testing.T) {
+	if got := FromHTTPStatus(http.StatusTeapot); got != Unknown {
+		t.Errorf("FromHTTPStatus(teapot) = %v, want %v", got, Unknown)
+	}
+}
+
+func TestWriteErrorNilIsNoOp(t *testing.T) {
+	w := httptest.NewRecorder()
+	WriteError(w, nil)
+	if w.Code != 200 {
+		t.Errorf("status = %d, want untouched default of 200", w.Code)
+	}
+	if w.Body.Len() != 0 {
+		t.Errorf("body = %q, want empty", w.Body.String())
+	}
+}
+
+func TestWriteError(t *testing.T) {
+	w := httptest.NewRecorder()
+	WriteError(w, New(NotFound, "no such tree"))
+
+	if got, want := w.Code, http.StatusNotFound; got != want {
+		t.Errorf("status = %d, want %d", got, want)
+	}
+
+	var body httpErrorBody
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("json.Unmarshal(%s) failed: %v", w.Body.String(), err)
+	}
+	if body.Code != "NotFound" {
+		t.Errorf("body.Code = %q, want %q", body.Code, "NotFound")
+	}
+	if body.Message != "no such tree" {
+		t.Errorf("body.Message = %q, want %q", body.Message, "no such tree")
+	}
+}
@@ -0,0 +1,187 @@
+// Copyright 2017 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package errors
+
+import (
+	"context"
+	"math/rand"
+	"time"
+)
+
+// retryableCodes classifies each Code as worth retrying or not, following
+// the litmus test documented on FailedPrecondition/Aborted/Unavailable
+// in errors.go: Unavailable and Aborted are the codes that test explicitly calls
+// out as retry-the-call/retry-at-a-higher-level, ResourceExhausted and
+// DeadlineExceeded are the other conditions that are typically transient.
+// Codes absent from this map (OK, Canceled, Unknown, Internal, DataLoss, and
+// any future addition) default to non-retryable; add an entry here if a new
+// code should be retryable.
+var retryableCodes = map[Code]bool{
+	Unavailable:       true,
+	Aborted:           true,
+	ResourceExhausted: true,
+	DeadlineExceeded:  true,
+
+	InvalidArgument:    false,
+	NotFound:           false,
+	AlreadyExists:      false,
+	PermissionDenied:   false,
+	Unauthenticated:    false,
+	FailedPrecondition: false,
+	Unimplemented:      false,
+	OutOfRange:         false,
+}
+
+// IsRetryable reports whether err is worth retrying, based on its Code (see
+// retryableCodes). A nil error, or one whose code isn't listed as
+// retryable, reports false.
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	return retryableCodes[ErrorCode(err)]
+}
+
+// WithRetryAfter returns a copy of err recording that the caller should wait
+// at least d before retrying, overriding whatever backoff Retry would
+// otherwise compute. If err is nil, nil is returned. If err is not a
+// TrillianError, it is wrapped in one that preserves its code (per
+// ErrorCode) and message, with err set as the cause so Unwrap still reaches
+// it.
+func WithRetryAfter(err error, d time.Duration) error {
+	if err == nil {
+		return nil
+	}
+	if te, ok := err.(*trillianError); ok {
+		cp := *te
+		cp.retryAfter = d
+		cp.hasRetryAfter = true
+		return &cp
+	}
+	return &trillianError{code: ErrorCode(err), message: err.Error(), cause: err, retryAfter: d, hasRetryAfter: true}
+}
+
+// RetryAfter returns the duration set by WithRetryAfter, walking the Unwrap
+// chain if err itself carries none. The second return value is false if no
+// RetryAfter hint is found anywhere in the chain.
+func RetryAfter(err error) (time.Duration, bool) {
+	for err != nil {
+		if te, ok := err.(*trillianError); ok && te.hasRetryAfter {
+			return te.retryAfter, true
+		}
+		unwrapper, ok := err.(interface{ Unwrap() error })
+		if !ok {
+			break
+		}
+		err = unwrapper.Unwrap()
+	}
+	return 0, false
+}
+
+// retryOptions holds the tunables for Retry; see the With* functions below.
+type retryOptions struct {
+	maxAttempts    int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	multiplier     float64
+}
+
+// RetryOption configures a call to Retry.
+type RetryOption func(*retryOptions)
+
+// WithMaxAttempts caps the number of times op is called, including the
+// first attempt. The default is 0, meaning retry until ctx is done.
+func WithMaxAttempts(n int) RetryOption {
+	return func(o *retryOptions) { o.maxAttempts = n }
+}
+
+// WithInitialBackoff sets the delay before the first retry. The default is
+// 100ms. Later retries back off exponentially from this value unless a
+// RetryAfter hint overrides them.
+func WithInitialBackoff(d time.Duration) RetryOption {
+	return func(o *retryOptions) { o.initialBackoff = d }
+}
+
+// WithMaxBackoff caps the exponential backoff delay (before jitter). The
+// default is 30s.
+func WithMaxBackoff(d time.Duration) RetryOption {
+	return func(o *retryOptions) { o.maxBackoff = d }
+}
+
+// WithBackoffMultiplier sets the factor the backoff delay is multiplied by
+// after each retry. The default is 2.
+func WithBackoffMultiplier(m float64) RetryOption {
+	return func(o *retryOptions) { o.multiplier = m }
+}
+
+// Retry calls op until it succeeds, returns a non-retryable error (per
+// IsRetryable), the attempt budget set by WithMaxAttempts is exhausted, or
+// ctx is done.
+//
+// Between attempts, Retry waits for an exponentially increasing backoff
+// (seeded by WithInitialBackoff, capped by WithMaxBackoff, scaled by
+// WithBackoffMultiplier) plus up to 50% jitter, so that callers retrying the
+// same failing RPC don't all retry in lockstep. If the failing error carries
+// a RetryAfter hint (see WithRetryAfter), that duration is used instead of
+// the computed backoff and the exponential sequence is not advanced,
+// honoring the server's explicit guidance.
+//
+// Retry removes the need for bespoke retry loops around every RPC call in
+// log-client and mirror code; callers just wrap the call in Retry and rely
+// on the Code-based IsRetryable classification to decide what's worth
+// retrying.
+func Retry(ctx context.Context, op func() error, opts ...RetryOption) error {
+	o := retryOptions{
+		initialBackoff: 100 * time.Millisecond,
+		maxBackoff:     30 * time.Second,
+		multiplier:     2,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	backoff := o.initialBackoff
+	for attempt := 1; ; attempt++ {
+		err := op()
+		if err == nil {
+			return nil
+		}
+		if !IsRetryable(err) {
+			return err
+		}
+		if o.maxAttempts > 0 && attempt >= o.maxAttempts {
+			return err
+		}
+
+		wait := backoff
+		if ra, ok := RetryAfter(err); ok {
+			wait = ra
+		} else {
+			backoff = time.Duration(float64(backoff) * o.multiplier)
+			if backoff > o.maxBackoff {
+				backoff = o.maxBackoff
+			}
+		}
+		if jitterMax := wait / 2; jitterMax > 0 {
+			wait += time.Duration(rand.Int63n(int64(jitterMax)))
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
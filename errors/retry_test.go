@@ -0,0 +1,153 @@
+// Copyright 2017 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package errors
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestIsRetryable(t *testing.T) {
+	for _, tc := range []struct {
+		code Code
+		want bool
+	}{
+		{Unavailable, true},
+		{Aborted, true},
+		{ResourceExhausted, true},
+		{DeadlineExceeded, true},
+		{InvalidArgument, false},
+		{NotFound, false},
+		{AlreadyExists, false},
+		{PermissionDenied, false},
+		{Unauthenticated, false},
+		{FailedPrecondition, false},
+		{Unimplemented, false},
+		{OutOfRange, false},
+		{OK, false},
+	} {
+		if got := IsRetryable(New(tc.code, "x")); got != tc.want {
+			t.Errorf("IsRetryable(code=%v) = %v, want %v", tc.code, got, tc.want)
+		}
+	}
+	if IsRetryable(nil) {
+		t.Error("IsRetryable(nil) = true, want false")
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	err := WithRetryAfter(New(Unavailable, "busy"), 42*time.Millisecond)
+	d, ok := RetryAfter(err)
+	if !ok || d != 42*time.Millisecond {
+		t.Errorf("RetryAfter() = (%v, %v), want (42ms, true)", d, ok)
+	}
+
+	wrapped := Wrap(Unavailable, err, "outer")
+	d, ok = RetryAfter(wrapped)
+	if !ok || d != 42*time.Millisecond {
+		t.Errorf("RetryAfter(wrapped) = (%v, %v), want (42ms, true)", d, ok)
+	}
+
+	if _, ok := RetryAfter(New(Unavailable, "no hint")); ok {
+		t.Error("RetryAfter() on error without hint = true, want false")
+	}
+}
+
+func TestRetrySucceedsEventually(t *testing.T) {
+	attempts := 0
+	err := Retry(context.Background(), func() error {
+		attempts++
+		if attempts < 3 {
+			return New(Unavailable, "try again")
+		}
+		return nil
+	}, WithInitialBackoff(time.Millisecond), WithMaxBackoff(5*time.Millisecond))
+
+	if err != nil {
+		t.Fatalf("Retry() = %v, want nil", err)
+	}
+	if attempts != 3 {
+		t.Errorf("attempts = %d, want 3", attempts)
+	}
+}
+
+func TestRetryNonRetryableFailsImmediately(t *testing.T) {
+	attempts := 0
+	want := New(NotFound, "gone")
+	err := Retry(context.Background(), func() error {
+		attempts++
+		return want
+	})
+
+	if err != want {
+		t.Errorf("Retry() = %v, want %v", err, want)
+	}
+	if attempts != 1 {
+		t.Errorf("attempts = %d, want 1", attempts)
+	}
+}
+
+func TestRetryRespectsMaxAttempts(t *testing.T) {
+	attempts := 0
+	err := Retry(context.Background(), func() error {
+		attempts++
+		return New(Unavailable, "still down")
+	}, WithMaxAttempts(3), WithInitialBackoff(time.Millisecond), WithMaxBackoff(5*time.Millisecond))
+
+	if IsRetryable(err) == false {
+		t.Errorf("Retry() final error code = %v, want a retryable code preserved", ErrorCode(err))
+	}
+	if attempts != 3 {
+		t.Errorf("attempts = %d, want 3", attempts)
+	}
+}
+
+func TestRetryHonorsRetryAfter(t *testing.T) {
+	attempts := 0
+	start := time.Now()
+	err := Retry(context.Background(), func() error {
+		attempts++
+		if attempts < 2 {
+			return WithRetryAfter(New(Unavailable, "slow down"), 20*time.Millisecond)
+		}
+		return nil
+	}, WithInitialBackoff(time.Nanosecond), WithMaxBackoff(time.Nanosecond))
+
+	if err != nil {
+		t.Fatalf("Retry() = %v, want nil", err)
+	}
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Errorf("Retry() took %v, want at least the 20ms RetryAfter hint", elapsed)
+	}
+}
+
+func TestRetryRespectsContextCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	attempts := 0
+	err := Retry(ctx, func() error {
+		attempts++
+		return New(Unavailable, "down")
+	}, WithInitialBackoff(time.Millisecond))
+
+	if err != context.Canceled {
+		t.Errorf("Retry() = %v, want context.Canceled", err)
+	}
+	if attempts != 1 {
+		t.Errorf("attempts = %d, want 1", attempts)
+	}
+}